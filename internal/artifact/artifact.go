@@ -0,0 +1,94 @@
+// Package artifact provides the Artifact type, which represents an
+// artifact produced during the release pipeline, and a thread-safe
+// collection to store and query them.
+package artifact
+
+import "sync"
+
+// Type defines the type of an artifact.
+type Type int
+
+const (
+	// Binary is a binary (usually in dist/GOOS_GOARCH/BINARY).
+	Binary Type = iota
+	// UploadableArchive is a tar.gz/zip archive meant to be uploaded.
+	UploadableArchive
+	// Checksum is a checksums file.
+	Checksum
+	// CArchive is a static library produced by `-buildmode=c-archive`.
+	CArchive
+	// CShared is a shared library produced by `-buildmode=c-shared`.
+	CShared
+	// Header is the C header accompanying a CShared or CArchive artifact.
+	Header
+	// GoMod is the resolved module list (path, version, h1 hash)
+	// produced by the gomod pipe.
+	GoMod
+)
+
+// Artifact represents an artifact and its relevant info.
+type Artifact struct {
+	Name   string
+	Path   string
+	Goos   string
+	Goarch string
+	Goarm  string
+	Gomips string
+	Type   Type
+	Extra  map[string]interface{}
+}
+
+// ExtraOr returns the Extra field with the given key, or the or value
+// specified if it doesn't exist.
+func (a Artifact) ExtraOr(key string, or interface{}) interface{} {
+	if a.Extra == nil {
+		return or
+	}
+	if v, ok := a.Extra[key]; ok {
+		return v
+	}
+	return or
+}
+
+// Artifacts is a collection of artifact.
+type Artifacts struct {
+	items []*Artifact
+	lock  sync.Mutex
+}
+
+// New returns a new Artifacts.
+func New() Artifacts {
+	return Artifacts{}
+}
+
+// List return the actual list of artifacts.
+func (artifacts *Artifacts) List() []*Artifact {
+	artifacts.lock.Lock()
+	defer artifacts.lock.Unlock()
+	return artifacts.items
+}
+
+// Add safely adds a new artifact to an artifact list.
+func (artifacts *Artifacts) Add(a *Artifact) {
+	artifacts.lock.Lock()
+	defer artifacts.lock.Unlock()
+	artifacts.items = append(artifacts.items, a)
+}
+
+// Filter filters the artifact list, returning a new instance.
+func (artifacts *Artifacts) Filter(filter func(a *Artifact) bool) Artifacts {
+	var result []*Artifact
+	for _, a := range artifacts.List() {
+		if filter(a) {
+			result = append(result, a)
+		}
+	}
+	return Artifacts{items: result}
+}
+
+// ByType returns a filter that only matches items of the given type.
+func ByType(t Type) func(a *Artifact) bool {
+	return func(a *Artifact) bool {
+		return a.Type == t
+	}
+}
@@ -0,0 +1,594 @@
+// Package golang provides a Builder implementation that uses the `go`
+// compiler toolchain to build Go projects.
+package golang
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func init() {
+	api.Register("go", Default)
+}
+
+// Default builder instance.
+var Default = &Builder{}
+
+// Builder is the golang builder, using `go build` under the hood.
+type Builder struct{}
+
+var (
+	validGoos = map[string]bool{
+		"aix":       true,
+		"darwin":    true,
+		"dragonfly": true,
+		"freebsd":   true,
+		"js":        true,
+		"linux":     true,
+		"netbsd":    true,
+		"openbsd":   true,
+		"plan9":     true,
+		"solaris":   true,
+		"windows":   true,
+	}
+
+	validGoarch = map[string]bool{
+		"386":      true,
+		"amd64":    true,
+		"arm":      true,
+		"arm64":    true,
+		"mips":     true,
+		"mipsle":   true,
+		"mips64":   true,
+		"mips64le": true,
+		"ppc64":    true,
+		"ppc64le":  true,
+		"riscv64":  true,
+		"s390x":    true,
+		"wasm":     true,
+	}
+
+	validGoarm = map[string]bool{
+		"5": true,
+		"6": true,
+		"7": true,
+	}
+
+	validGomips = map[string]bool{
+		"hardfloat": true,
+		"softfloat": true,
+	}
+
+	validBuildmodes = map[string]bool{
+		"exe":       true,
+		"pie":       true,
+		"plugin":    true,
+		"c-archive": true,
+		"c-shared":  true,
+	}
+)
+
+// matrix of goos/goarch combinations that the Go toolchain actually
+// supports, mirroring `go tool dist list`. Only the combinations
+// exercised by this builder's defaults and tests are listed here.
+var validTargets = map[string]bool{
+	"linux_386":      true,
+	"linux_amd64":    true,
+	"linux_arm":      true,
+	"linux_arm64":    true,
+	"linux_mips":     true,
+	"linux_mipsle":   true,
+	"linux_mips64":   true,
+	"linux_mips64le": true,
+	"darwin_amd64":   true,
+	"darwin_arm64":   true,
+	"windows_386":    true,
+	"windows_amd64":  true,
+	"windows_arm":    true,
+	"freebsd_386":    true,
+	"freebsd_amd64":  true,
+	"freebsd_arm":    true,
+	"js_wasm":        true,
+}
+
+// buildmodeTargets restricts which GOOS_GOARCH combos support a given
+// non-default buildmode, matching the Go toolchain's own support
+// matrix (see `go help buildmode`).
+var buildmodeTargets = map[string][]string{
+	"plugin":    {"linux_amd64", "linux_arm64", "darwin_amd64", "darwin_arm64"},
+	"c-archive": {"linux_amd64", "linux_arm64", "darwin_amd64", "darwin_arm64", "windows_amd64"},
+	"c-shared":  {"linux_amd64", "linux_arm64", "darwin_amd64", "darwin_arm64", "windows_amd64"},
+}
+
+// WithDefaults sets the defaults for a build and returns it.
+func (*Builder) WithDefaults(build config.Build) (config.Build, error) {
+	for _, g := range build.Goos {
+		if !validGoos[g] {
+			return build, fmt.Errorf("invalid goos: %s", g)
+		}
+	}
+	for _, g := range build.Goarch {
+		if !validGoarch[g] {
+			return build, fmt.Errorf("invalid goarch: %s", g)
+		}
+	}
+	for _, g := range build.Goarm {
+		if !validGoarm[g] {
+			return build, fmt.Errorf("invalid goarm: %s", g)
+		}
+	}
+	for _, g := range build.Gomips {
+		if !validGomips[g] {
+			return build, fmt.Errorf("invalid gomips: %s", g)
+		}
+	}
+	if build.Buildmode != "" && !validBuildmodes[build.Buildmode] {
+		return build, fmt.Errorf("invalid buildmode: %s", build.Buildmode)
+	}
+	if build.Compiler != "" && !validCompilers[build.Compiler] {
+		return build, fmt.Errorf("invalid compiler: %s", build.Compiler)
+	}
+
+	if build.Binary == "" {
+		build.Binary = build.ID
+	}
+	if build.Main == "" {
+		build.Main = "."
+	}
+	if len(build.Goos) == 0 {
+		build.Goos = []string{"linux", "darwin"}
+	}
+	if len(build.Goarch) == 0 {
+		build.Goarch = []string{"amd64", "386", "arm64"}
+	}
+	if len(build.Goarm) == 0 {
+		build.Goarm = []string{"6"}
+	}
+	if len(build.Gomips) == 0 {
+		build.Gomips = []string{"hardfloat"}
+	}
+	if build.Compiler == "" {
+		build.Compiler = "gc"
+	}
+	if build.GoBinary == "" {
+		if build.Compiler == "gccgo" {
+			build.GoBinary = "gccgo"
+		} else {
+			build.GoBinary = "go"
+		}
+	}
+
+	if len(build.Targets) == 0 {
+		targets, err := matrix(build)
+		if err != nil {
+			return build, err
+		}
+		build.Targets = targets
+	}
+
+	if build.Buildmode != "" && build.Buildmode != "exe" && build.Buildmode != "pie" {
+		if err := validateBuildmodeTargets(build); err != nil {
+			return build, err
+		}
+	}
+
+	if build.Compiler == "gccgo" {
+		for _, t := range build.Targets {
+			target, err := newBuildTarget(t)
+			if err != nil {
+				return build, err
+			}
+			if err := validateGccgoTarget(target); err != nil {
+				return build, err
+			}
+		}
+	}
+
+	return build, nil
+}
+
+func matrix(build config.Build) ([]string, error) {
+	var targets []string
+	for _, goos := range build.Goos {
+		for _, goarch := range build.Goarch {
+			if !validTargets[goos+"_"+goarch] {
+				continue
+			}
+			if ignored(build, goos, goarch, "", "") {
+				continue
+			}
+			if goarch == "arm" {
+				for _, goarm := range build.Goarm {
+					if ignored(build, goos, goarch, goarm, "") {
+						continue
+					}
+					targets = append(targets, fmt.Sprintf("%s_%s_%s", goos, goarch, goarm))
+				}
+				continue
+			}
+			if strings.HasPrefix(goarch, "mips") {
+				for _, gomips := range build.Gomips {
+					if ignored(build, goos, goarch, "", gomips) {
+						continue
+					}
+					targets = append(targets, fmt.Sprintf("%s_%s_%s", goos, goarch, gomips))
+				}
+				continue
+			}
+			targets = append(targets, fmt.Sprintf("%s_%s", goos, goarch))
+		}
+	}
+	return targets, nil
+}
+
+func ignored(build config.Build, goos, goarch, goarm, gomips string) bool {
+	for _, ign := range build.Ignore {
+		if ign.Goos != "" && ign.Goos != goos {
+			continue
+		}
+		if ign.Goarch != "" && ign.Goarch != goarch {
+			continue
+		}
+		if ign.Goarm != "" && ign.Goarm != goarm {
+			continue
+		}
+		if ign.Gomips != "" && ign.Gomips != gomips {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func validateBuildmodeTargets(build config.Build) error {
+	supported := buildmodeTargets[build.Buildmode]
+	for _, target := range build.Targets {
+		var ok bool
+		for _, s := range supported {
+			if strings.HasPrefix(target, s) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("buildmode %s is not supported for target %s", build.Buildmode, target)
+		}
+	}
+	return nil
+}
+
+// Build builds a single binary for the given target.
+func (*Builder) Build(ctx *context.Context, build config.Build, options api.Options) error {
+	target, err := newBuildTarget(options.Target)
+	if err != nil {
+		return err
+	}
+
+	dir := build.Dir
+	if dir == "" {
+		dir = "."
+	}
+
+	goBinary := build.GoBinary
+	if goBinary == "" {
+		goBinary = "go"
+	}
+
+	mainFile, err := findMain(build, dir)
+	if err != nil {
+		return err
+	}
+
+	env := append(os.Environ(), build.Env...)
+	if build.Compiler != "gccgo" {
+		env = append(env, target.Env()...)
+	}
+
+	outputPath := buildmodeOutputPath(options, build.Buildmode)
+
+	artifactExtras := map[string]interface{}{
+		"Binary": strings.TrimSuffix(filepath.Base(options.Name), options.Ext),
+		"Ext":    options.Ext,
+		"ID":     build.ID,
+	}
+
+	a := &artifact.Artifact{
+		Name:   options.Name,
+		Path:   outputPath,
+		Goos:   target.os,
+		Goarch: target.arch,
+		Goarm:  target.arm,
+		Gomips: target.mips,
+		Type:   artifact.Binary,
+		Extra:  artifactExtras,
+	}
+
+	artifactTmpl := tmpl.New(ctx).WithArtifact(a, map[string]string{})
+
+	var flags []string
+	if build.Compiler == "gccgo" {
+		flags, err = gccgoBuildFlags(ctx, artifactTmpl, build, target)
+		if err != nil {
+			return err
+		}
+	} else {
+		flags, err = buildFlags(ctx, artifactTmpl, build)
+		if err != nil {
+			return err
+		}
+	}
+
+	useCache := ctx.Config.Cache && !ctx.NoCache
+	var cDir, key string
+	if useCache {
+		key, err = cacheKey(ctx, build, target, goBinary, dir, flags)
+		if err != nil {
+			return err
+		}
+		cDir = cacheDir(filepath.Dir(filepath.Dir(outputPath)))
+		hit, err := cacheHit(cDir, key, outputPath)
+		if err != nil {
+			return err
+		}
+		if hit {
+			addBuildArtifacts(ctx, a, build.Buildmode)
+			return nil
+		}
+	}
+
+	if build.Compiler == "gccgo" {
+		args := append([]string{}, flags...)
+		args = append(args, "-o", outputPath, mainFile)
+		if err := run(goBinary, args, dir, env); err != nil {
+			return err
+		}
+	} else {
+		args := []string{"build"}
+		if build.Vendor {
+			args = append(args, "-mod=vendor")
+		}
+		if build.Buildmode != "" {
+			args = append(args, "-buildmode="+build.Buildmode)
+		}
+		args = append(args, flags...)
+		args = append(args, "-o", outputPath, mainFile)
+		if err := run(goBinary, args, dir, env); err != nil {
+			return err
+		}
+	}
+
+	if useCache {
+		if err := cacheStore(cDir, key, outputPath); err != nil {
+			return err
+		}
+	}
+
+	if build.ModTimestamp != "" {
+		modUnix, err := strconv.ParseInt(build.ModTimestamp, 10, 64)
+		if err != nil {
+			return err
+		}
+		modTime := time.Unix(modUnix, 0)
+		if err := os.Chtimes(outputPath, modTime, modTime); err != nil {
+			return err
+		}
+	}
+
+	addBuildArtifacts(ctx, a, build.Buildmode)
+	return nil
+}
+
+// buildmodeOutputPath adjusts the produced artifact's path to carry
+// the extension the Go toolchain actually writes for the given
+// buildmode, regardless of the extension the caller requested.
+func buildmodeOutputPath(options api.Options, buildmode string) string {
+	ext := options.Ext
+	switch buildmode {
+	case "c-archive":
+		ext = ".a"
+	case "c-shared", "plugin":
+		ext = ".so"
+	default:
+		return options.Path
+	}
+	return strings.TrimSuffix(options.Path, options.Ext) + ext
+}
+
+// addBuildArtifacts registers the built artifact (and, for
+// c-archive/c-shared buildmodes, its companion `.h` header), setting
+// the artifact type from the buildmode.
+func addBuildArtifacts(ctx *context.Context, a *artifact.Artifact, buildmode string) {
+	switch buildmode {
+	case "c-archive":
+		a.Type = artifact.CArchive
+		ctx.Artifacts.Add(a)
+		ctx.Artifacts.Add(headerArtifact(a))
+	case "c-shared":
+		a.Type = artifact.CShared
+		ctx.Artifacts.Add(a)
+		ctx.Artifacts.Add(headerArtifact(a))
+	default:
+		ctx.Artifacts.Add(a)
+	}
+}
+
+// headerArtifact returns the `.h` companion header that `go build`
+// writes alongside a c-archive/c-shared artifact.
+func headerArtifact(bin *artifact.Artifact) *artifact.Artifact {
+	return &artifact.Artifact{
+		Name:   strings.TrimSuffix(bin.Path, filepath.Ext(bin.Path)) + ".h",
+		Path:   strings.TrimSuffix(bin.Path, filepath.Ext(bin.Path)) + ".h",
+		Goos:   bin.Goos,
+		Goarch: bin.Goarch,
+		Goarm:  bin.Goarm,
+		Gomips: bin.Gomips,
+		Type:   artifact.Header,
+		Extra:  bin.Extra,
+	}
+}
+
+func buildFlags(ctx *context.Context, t *tmpl.Template, build config.Build) ([]string, error) {
+	asmflags, err := processFlags(ctx, nil, []string{}, build.Asmflags, "-asmflags=")
+	if err != nil {
+		return nil, err
+	}
+	gcflags, err := processFlags(ctx, nil, []string{}, build.Gcflags, "-gcflags=")
+	if err != nil {
+		return nil, err
+	}
+	ldflags, err := joinLdFlagsTmpl(t, build.Ldflags)
+	if err != nil {
+		return nil, err
+	}
+	flags, err := processFlags(ctx, nil, []string{}, build.Flags, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	result = append(result, flags...)
+	result = append(result, asmflags...)
+	result = append(result, gcflags...)
+	if ldflags != "" {
+		result = append(result, ldflags)
+	}
+	return result, nil
+}
+
+func joinLdFlagsTmpl(t *tmpl.Template, ldflags []string) (string, error) {
+	var applied []string
+	for _, l := range ldflags {
+		s, err := t.Apply(l)
+		if err != nil {
+			return "", err
+		}
+		applied = append(applied, s)
+	}
+	if len(applied) == 0 {
+		return "", nil
+	}
+	return joinLdFlags(applied), nil
+}
+
+func joinLdFlags(ldflags []string) string {
+	return "-ldflags=" + strings.Join(ldflags, " ")
+}
+
+func processFlags(ctx *context.Context, a *artifact.Artifact, env, flags []string, prefix string) ([]string, error) {
+	var result []string
+	for _, rawFlag := range flags {
+		tf := tmpl.New(ctx)
+		if a != nil {
+			tf = tf.WithArtifact(a, map[string]string{})
+		}
+		flag, err := tf.Apply(rawFlag)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, prefix+flag)
+	}
+	return result, nil
+}
+
+func run(goBinary string, args []string, dir string, env []string) error {
+	/* #nosec */
+	cmd := exec.Command(goBinary, args...)
+	cmd.Env = env
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w", string(out), err)
+	}
+	return nil
+}
+
+type buildTarget struct {
+	os, arch, arm, mips string
+}
+
+func (t buildTarget) Env() []string {
+	env := []string{
+		"GOOS=" + t.os,
+		"GOARCH=" + t.arch,
+	}
+	if t.arm != "" {
+		env = append(env, "GOARM="+t.arm)
+	}
+	if t.mips != "" {
+		env = append(env, "GOMIPS="+t.mips)
+	}
+	return env
+}
+
+func newBuildTarget(s string) (buildTarget, error) {
+	parts := strings.Split(s, "_")
+	if len(parts) < 2 {
+		return buildTarget{}, fmt.Errorf("%s is not a valid build target", s)
+	}
+	t := buildTarget{os: parts[0], arch: parts[1]}
+	if len(parts) > 2 {
+		if t.arch == "arm" {
+			t.arm = parts[2]
+		} else {
+			t.mips = parts[2]
+		}
+	}
+	return t, nil
+}
+
+func findMain(build config.Build, dir string) (string, error) {
+	main := build.Main
+	if main == "" || main == "." || strings.HasSuffix(main, "...") {
+		return findMainGlob(build, dir)
+	}
+	path := filepath.Join(dir, main)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("couldn't find main file: %w", err)
+	}
+	if !hasMainFunc(path) {
+		return "", fmt.Errorf("build for %s does not contain a main function", buildName(build))
+	}
+	return main, nil
+}
+
+func findMainGlob(build config.Build, dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if hasMainFunc(f) {
+			rel, err := filepath.Rel(dir, f)
+			if err != nil {
+				return "", err
+			}
+			return rel, nil
+		}
+	}
+	return "", fmt.Errorf("build for %s does not contain a main function", buildName(build))
+}
+
+func buildName(build config.Build) string {
+	if build.ID != "" {
+		return build.ID
+	}
+	return build.Binary
+}
+
+func hasMainFunc(file string) bool {
+	bts, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(bts), "func main()")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -773,6 +774,156 @@ func TestBuildModTimestamp(t *testing.T) {
 	}
 }
 
+func TestBuildCacheHitPreservesBuildmodeArtifacts(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	writeGoodMain(t, folder)
+	config := config.Project{
+		Cache: true,
+		Builds: []config.Build{
+			{
+				ID:        "foo",
+				Env:       []string{"GO111MODULE=off"},
+				Binary:    "foo",
+				Buildmode: "c-shared",
+				Targets:   []string{runtimeTarget},
+				GoBinary:  "go",
+			},
+		},
+	}
+	ctx := context.New(config)
+	ctx.Git.CurrentTag = "5.6.7"
+	build := ctx.Config.Builds[0]
+	opts := api.Options{
+		Target: runtimeTarget,
+		Name:   build.Binary,
+		Path:   filepath.Join(folder, "dist", runtimeTarget, build.Binary),
+	}
+
+	require.NoError(t, Default.Build(ctx, build, opts))
+	require.NoError(t, Default.Build(ctx, build, opts)) // second run should hit the cache
+
+	var gotTypes []artifact.Type
+	for _, a := range ctx.Artifacts.List() {
+		gotTypes = append(gotTypes, a.Type)
+	}
+	require.Contains(t, gotTypes, artifact.CShared)
+	require.Contains(t, gotTypes, artifact.Header)
+}
+
+func TestBuildBuildmodes(t *testing.T) {
+	for buildmode, tc := range map[string]struct {
+		extras []artifact.Type
+		ext    string
+	}{
+		"exe":       {nil, ""},
+		"pie":       {nil, ""},
+		"plugin":    {nil, ".so"},
+		"c-archive": {[]artifact.Type{artifact.CArchive, artifact.Header}, ".a"},
+		"c-shared":  {[]artifact.Type{artifact.CShared, artifact.Header}, ".so"},
+	} {
+		extras := tc.extras
+		t.Run(buildmode, func(t *testing.T) {
+			folder := testlib.Mktmp(t)
+			writeGoodMain(t, folder)
+			config := config.Project{
+				Builds: []config.Build{
+					{
+						ID:        "foo",
+						Env:       []string{"GO111MODULE=off"},
+						Binary:    "foo",
+						Buildmode: buildmode,
+						Targets:   []string{runtimeTarget},
+						GoBinary:  "go",
+					},
+				},
+			}
+			ctx := context.New(config)
+			ctx.Git.CurrentTag = "5.6.7"
+			build := ctx.Config.Builds[0]
+			err := Default.Build(ctx, build, api.Options{
+				Target: runtimeTarget,
+				Name:   build.Binary,
+				Path:   filepath.Join(folder, "dist", runtimeTarget, build.Binary),
+			})
+			require.NoError(t, err)
+
+			var gotTypes []artifact.Type
+			for _, a := range ctx.Artifacts.List() {
+				gotTypes = append(gotTypes, a.Type)
+				if a.Type == artifact.Binary || a.Type == artifact.CArchive || a.Type == artifact.CShared {
+					require.Equal(t, tc.ext, filepath.Ext(a.Path))
+				}
+			}
+			for _, extra := range extras {
+				require.Contains(t, gotTypes, extra)
+			}
+		})
+	}
+}
+
+func TestWithDefaultsGccgo(t *testing.T) {
+	build, err := Default.WithDefaults(config.Build{
+		ID:       "foo",
+		Binary:   "foo",
+		Compiler: "gccgo",
+		Targets:  []string{"linux_amd64", "linux_arm"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gccgo", build.GoBinary)
+}
+
+func TestWithDefaultsGccgoInvalidTarget(t *testing.T) {
+	_, err := Default.WithDefaults(config.Build{
+		ID:       "foo",
+		Binary:   "foo",
+		Compiler: "gccgo",
+		Targets:  []string{"js_wasm"},
+	})
+	require.EqualError(t, err, "gccgo does not support js_wasm")
+}
+
+func TestBuildGccgo(t *testing.T) {
+	if _, err := exec.LookPath("gccgo"); err != nil {
+		t.Skip("gccgo not available")
+	}
+	folder := testlib.Mktmp(t)
+	writeGoodMain(t, folder)
+	config := config.Project{
+		Builds: []config.Build{
+			{
+				ID:       "foo",
+				Env:      []string{"GO111MODULE=off"},
+				Binary:   "foo",
+				Compiler: "gccgo",
+				Gcflags:  []string{"all=-N -l"},
+				Ldflags:  []string{"-X main.version=1.2.3"},
+				Targets:  []string{"linux_amd64"},
+				GoBinary: "gccgo",
+			},
+		},
+	}
+	ctx := context.New(config)
+	ctx.Git.CurrentTag = "5.6.7"
+	build := ctx.Config.Builds[0]
+	err := Default.Build(ctx, build, api.Options{
+		Target: "linux_amd64",
+		Name:   "foo",
+		Path:   filepath.Join(folder, "dist", "linux_amd64", "foo"),
+	})
+	require.NoError(t, err)
+}
+
+func TestBuildInvalidBuildmodeTarget(t *testing.T) {
+	build, err := Default.WithDefaults(config.Build{
+		ID:        "foo",
+		Binary:    "foo",
+		Buildmode: "plugin",
+		Targets:   []string{"js_wasm"},
+	})
+	require.EqualError(t, err, "buildmode plugin is not supported for target js_wasm")
+	require.Equal(t, "plugin", build.Buildmode)
+}
+
 //
 // Helpers
 //
@@ -0,0 +1,203 @@
+package golang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const cacheManifestName = "manifest.json"
+
+// cacheEntry records where a previously built artifact for a given
+// content hash can be found.
+type cacheEntry struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// cacheDir returns the directory goreleaser stores its build cache in,
+// rooted at the dist folder being used for this run.
+func cacheDir(distDir string) string {
+	return filepath.Join(distDir, ".cache")
+}
+
+// cacheKey computes a content hash over everything that can influence
+// the output of a build: the resolved flags, env, go version, target
+// triple and the hash of the source tree being compiled.
+func cacheKey(ctx *context.Context, build config.Build, target buildTarget, goBinary, dir string, flags []string) (string, error) {
+	h := sha256.New()
+
+	fmt.Fprintln(h, strings.Join(flags, " "))
+	fmt.Fprintln(h, strings.Join(build.Env, ","))
+	fmt.Fprintln(h, build.Main)
+	fmt.Fprintln(h, build.Buildmode)
+	fmt.Fprintln(h, target.os, target.arch, target.arm, target.mips)
+
+	goVersion, err := goVersionOutput(goBinary)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(h, goVersion)
+
+	srcHash, err := hashSourceTree(dir)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(h, srcHash)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func goVersionOutput(goBinary string) (string, error) {
+	/* #nosec */
+	out, err := exec.Command(goBinary, "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not get %s version: %w", goBinary, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// hashSourceTree walks dir hashing every .go file, go.mod and go.sum,
+// skipping vendored trees. Changes to any other file (including files
+// under a vendor directory) do not change the hash.
+func hashSourceTree(dir string) (string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintln(h, f)
+		/* #nosec */
+		fh, err := os.Open(f)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(h, fh); err != nil {
+			fh.Close()
+			return "", err
+		}
+		fh.Close()
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func loadCacheManifest(dir string) (map[string]cacheEntry, error) {
+	manifest := map[string]cacheEntry{}
+	bts, err := os.ReadFile(filepath.Join(dir, cacheManifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(bts, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func saveCacheManifest(dir string, manifest map[string]cacheEntry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	bts, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, cacheManifestName), bts, 0o644)
+}
+
+// cacheHit copies the cached artifact for key into path, if any, and
+// reports whether it found one.
+func cacheHit(cDir, key, path string) (bool, error) {
+	manifest, err := loadCacheManifest(cDir)
+	if err != nil {
+		return false, err
+	}
+	entry, ok := manifest[key]
+	if !ok {
+		return false, nil
+	}
+	if _, err := os.Stat(entry.Path); err != nil {
+		return false, nil
+	}
+	if err := copyFile(entry.Path, path); err != nil {
+		return false, err
+	}
+	modTime := entry.ModTime
+	return true, os.Chtimes(path, modTime, modTime)
+}
+
+// cacheStore copies the built artifact at path into the cache and
+// records it under key.
+func cacheStore(cDir, key, path string) error {
+	if err := os.MkdirAll(cDir, 0o755); err != nil {
+		return err
+	}
+	cached := filepath.Join(cDir, key)
+	if err := copyFile(path, cached); err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	manifest, err := loadCacheManifest(cDir)
+	if err != nil {
+		return err
+	}
+	manifest[key] = cacheEntry{Path: cached, ModTime: fi.ModTime()}
+	return saveCacheManifest(cDir, manifest)
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	/* #nosec */
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	/* #nosec */
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
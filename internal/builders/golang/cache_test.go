@@ -0,0 +1,73 @@
+package golang
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKeyChangesOnInputs(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	writeGoodMain(t, folder)
+
+	ctx := context.New(config.Project{})
+	build := config.Build{ID: "foo", Env: []string{"FOO=bar"}, GoBinary: "go"}
+	target := buildTarget{os: "linux", arch: "amd64"}
+
+	base, err := cacheKey(ctx, build, target, "go", folder, []string{"-ldflags=-s -w"})
+	require.NoError(t, err)
+
+	t.Run("flags change", func(t *testing.T) {
+		key, err := cacheKey(ctx, build, target, "go", folder, []string{"-ldflags=-s"})
+		require.NoError(t, err)
+		require.NotEqual(t, base, key)
+	})
+
+	t.Run("env changes", func(t *testing.T) {
+		b := build
+		b.Env = []string{"FOO=baz"}
+		key, err := cacheKey(ctx, b, target, "go", folder, []string{"-ldflags=-s -w"})
+		require.NoError(t, err)
+		require.NotEqual(t, base, key)
+	})
+
+	t.Run("source changes", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(
+			filepath.Join(folder, "main.go"),
+			[]byte("package main\nvar a = 2\nfunc main() {println(1)}"),
+			0o644,
+		))
+		key, err := cacheKey(ctx, build, target, "go", folder, []string{"-ldflags=-s -w"})
+		require.NoError(t, err)
+		require.NotEqual(t, base, key)
+	})
+}
+
+func TestCacheHitAndStore(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	cDir := filepath.Join(folder, "dist", ".cache")
+
+	src := filepath.Join(folder, "bin")
+	require.NoError(t, ioutil.WriteFile(src, []byte("binary-contents"), 0o755))
+
+	hit, err := cacheHit(cDir, "somekey", filepath.Join(folder, "restored"))
+	require.NoError(t, err)
+	require.False(t, hit, "should not hit an empty cache")
+
+	require.NoError(t, cacheStore(cDir, "somekey", src))
+
+	dst := filepath.Join(folder, "restored")
+	hit, err = cacheHit(cDir, "somekey", dst)
+	require.NoError(t, err)
+	require.True(t, hit)
+
+	contents, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "binary-contents", string(contents))
+}
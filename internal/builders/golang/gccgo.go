@@ -0,0 +1,131 @@
+package golang
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// gccgoBuildFlags builds the argument list passed to the gccgo driver
+// for a gccgo compile, translating the gc-oriented build config into what
+// gccgo's driver understands.
+func gccgoBuildFlags(ctx *context.Context, t *tmpl.Template, build config.Build, target buildTarget) ([]string, error) {
+	gccgoDropAsmflags(build.Asmflags)
+
+	multilib, err := gccgoMultilibFlag(target)
+	if err != nil {
+		return nil, err
+	}
+
+	rawFlags, err := processFlags(ctx, nil, []string{}, build.Flags, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var rawLdflags []string
+	for _, l := range build.Ldflags {
+		s, err := t.Apply(l)
+		if err != nil {
+			return nil, err
+		}
+		rawLdflags = append(rawLdflags, s)
+	}
+
+	var flags []string
+	flags = append(flags, rawFlags...)
+	flags = append(flags, multilib)
+	flags = append(flags, gccgoGcflags(build.Gcflags)...)
+	flags = append(flags, gccgoLdflags(rawLdflags)...)
+	return flags, nil
+}
+
+var validCompilers = map[string]bool{
+	"gc":    true,
+	"gccgo": true,
+}
+
+// gccgoTargets restricts build targets to the ones gccgo actually
+// knows how to cross-compile for on this builder; gccgo has no GOOS
+// concept of its own, so only linux triples are supported.
+var gccgoTargets = map[string]bool{
+	"linux_386":   true,
+	"linux_amd64": true,
+	"linux_arm":   true,
+}
+
+// gccgoMultilibFlag returns the -m flag/-march flag gccgo needs to
+// cross-compile to the given target, mirroring gcc's own multilib
+// naming.
+func gccgoMultilibFlag(target buildTarget) (string, error) {
+	switch target.arch {
+	case "amd64":
+		return "-m64", nil
+	case "386":
+		return "-m32", nil
+	case "arm":
+		return "-march=armv7-a", nil
+	default:
+		return "", fmt.Errorf("gccgo does not support %s_%s", target.os, target.arch)
+	}
+}
+
+func validateGccgoTarget(target buildTarget) error {
+	key := target.os + "_" + target.arch
+	if !gccgoTargets[key] {
+		return fmt.Errorf("gccgo does not support %s", key)
+	}
+	return nil
+}
+
+var xFlagRe = regexp.MustCompile(`-X\s+(\S+)=(\S+)`)
+
+// gccgoLdflags translates goreleaser's `-X k=v` ldflags convention
+// (only meaningful to the gc linker) into the `-Wl,--defsym`/
+// `-Xlinker` sequence gccgo's linker understands.
+func gccgoLdflags(ldflags []string) []string {
+	var out []string
+	for _, l := range ldflags {
+		matches := xFlagRe.FindAllStringSubmatch(l, -1)
+		if len(matches) == 0 {
+			out = append(out, l)
+			continue
+		}
+		for _, m := range matches {
+			out = append(out, "-Xlinker", fmt.Sprintf("--defsym=%s=%s", m[1], m[2]))
+		}
+	}
+	return out
+}
+
+// gccgoGcflags translates goreleaser's `<pattern>=<flags>` gcflags
+// entries into the `-g<N>`/`-O<N>` flags gccgo's driver expects.
+func gccgoGcflags(gcflags []string) []string {
+	var out []string
+	for _, g := range gcflags {
+		parts := strings.SplitN(g, "=", 2)
+		flags := parts[len(parts)-1]
+		switch {
+		case strings.Contains(flags, "-N"):
+			out = append(out, "-O0", "-g")
+		case flags == "":
+			continue
+		default:
+			out = append(out, "-O2")
+		}
+	}
+	return out
+}
+
+// gccgoDropAsmflags warns that gccgo has no equivalent to the gc
+// assembler flags and drops them rather than failing the build.
+func gccgoDropAsmflags(asmflags []string) {
+	if len(asmflags) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "warning: asmflags are not supported by gccgo and will be ignored")
+}
@@ -0,0 +1,211 @@
+// Package rust provides a Builder implementation that drives `cargo
+// build` to compile Rust projects.
+package rust
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func init() {
+	api.Register("rust", Default)
+}
+
+// Default builder instance.
+var Default = &Builder{}
+
+// Builder is the rust builder, using `cargo build` under the hood.
+type Builder struct{}
+
+// rustTargets maps goreleaser's GOOS_GOARCH targets to the Rust
+// target triples `rustc`/`cargo` expect.
+var rustTargets = map[string]string{
+	"linux_amd64":   "x86_64-unknown-linux-gnu",
+	"linux_386":     "i686-unknown-linux-gnu",
+	"linux_arm64":   "aarch64-unknown-linux-gnu",
+	"linux_arm":     "armv7-unknown-linux-gnueabihf",
+	"darwin_amd64":  "x86_64-apple-darwin",
+	"darwin_arm64":  "aarch64-apple-darwin",
+	"windows_amd64": "x86_64-pc-windows-gnu",
+	"windows_386":   "i686-pc-windows-gnu",
+}
+
+// WithDefaults sets the defaults for a build and returns it.
+func (*Builder) WithDefaults(build config.Build) (config.Build, error) {
+	if len(build.Targets) == 0 {
+		build.Targets = []string{"linux_amd64", "darwin_amd64", "windows_amd64"}
+	}
+	for _, target := range build.Targets {
+		if _, ok := rustTargets[target]; !ok {
+			return build, fmt.Errorf("invalid rust target: %s", target)
+		}
+	}
+	return build, nil
+}
+
+// Build builds a single binary for the given target.
+func (*Builder) Build(ctx *context.Context, build config.Build, options api.Options) error {
+	triple, ok := rustTargets[options.Target]
+	if !ok {
+		return fmt.Errorf("%s is not a valid build target", options.Target)
+	}
+
+	dir := build.Dir
+	if dir == "" {
+		dir = "."
+	}
+
+	binary := build.Binary
+	if binary == "" {
+		name, err := binaryNameFromCargoToml(dir)
+		if err != nil {
+			return err
+		}
+		binary = name
+	}
+
+	env := append(os.Environ(), build.Env...)
+
+	a := &artifact.Artifact{
+		Name:   strings.TrimSuffix(options.Name, options.Ext),
+		Path:   options.Path,
+		Goos:   strings.SplitN(options.Target, "_", 2)[0],
+		Goarch: strings.SplitN(options.Target, "_", 2)[1],
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"Ext":    options.Ext,
+			"Binary": binary,
+			"ID":     build.ID,
+		},
+	}
+
+	artifactTmpl := tmpl.New(ctx).WithArtifact(a, map[string]string{})
+
+	flags, err := processFlags(ctx, build.Flags)
+	if err != nil {
+		return err
+	}
+
+	rustflags, err := rustLdflags(artifactTmpl, build.Ldflags)
+	if err != nil {
+		return err
+	}
+	if rustflags != "" {
+		env = append(env, "RUSTFLAGS="+rustflags)
+	}
+
+	args := []string{"build", "--release", "--target", triple}
+	args = append(args, flags...)
+
+	if err := run(args, dir, env); err != nil {
+		return err
+	}
+
+	builtPath := filepath.Join(dir, "target", triple, "release", binary+options.Ext)
+	if err := copyFile(builtPath, options.Path); err != nil {
+		return err
+	}
+
+	if build.ModTimestamp != "" {
+		modUnix, err := strconv.ParseInt(build.ModTimestamp, 10, 64)
+		if err != nil {
+			return err
+		}
+		modTime := time.Unix(modUnix, 0)
+		if err := os.Chtimes(options.Path, modTime, modTime); err != nil {
+			return err
+		}
+	}
+
+	ctx.Artifacts.Add(a)
+	return nil
+}
+
+func processFlags(ctx *context.Context, flags []string) ([]string, error) {
+	var result []string
+	for _, rawFlag := range flags {
+		flag, err := tmpl.New(ctx).Apply(rawFlag)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, flag)
+	}
+	return result, nil
+}
+
+// rustLdflags translates goreleaser ldflags entries into the
+// `-C link-arg=...` sequence `RUSTFLAGS` expects.
+func rustLdflags(t *tmpl.Template, ldflags []string) (string, error) {
+	var args []string
+	for _, l := range ldflags {
+		applied, err := t.Apply(l)
+		if err != nil {
+			return "", err
+		}
+		for _, arg := range strings.Fields(applied) {
+			args = append(args, "-C", "link-arg="+arg)
+		}
+	}
+	return strings.Join(args, " "), nil
+}
+
+func binaryNameFromCargoToml(dir string) (string, error) {
+	var manifest struct {
+		Package struct {
+			Name string `toml:"name"`
+		} `toml:"package"`
+	}
+	if _, err := toml.DecodeFile(filepath.Join(dir, "Cargo.toml"), &manifest); err != nil {
+		return "", fmt.Errorf("couldn't read Cargo.toml: %w", err)
+	}
+	if manifest.Package.Name == "" {
+		return "", fmt.Errorf("Cargo.toml has no package name")
+	}
+	return manifest.Package.Name, nil
+}
+
+func run(args []string, dir string, env []string) error {
+	/* #nosec */
+	cmd := exec.Command("cargo", args...)
+	cmd.Env = env
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w", string(out), err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	/* #nosec */
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	/* #nosec */
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,153 @@
+package rust
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func goosGoarch() string {
+	switch runtime.GOOS + "_" + runtime.GOARCH {
+	case "darwin_amd64", "darwin_arm64", "linux_amd64", "linux_386", "linux_arm64", "windows_amd64", "windows_386":
+		return runtime.GOOS + "_" + runtime.GOARCH
+	default:
+		return "linux_amd64"
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	build, err := Default.WithDefaults(config.Build{ID: "foo"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, build.Targets, []string{"linux_amd64", "darwin_amd64", "windows_amd64"})
+}
+
+func TestWithDefaultsCustomTargets(t *testing.T) {
+	build, err := Default.WithDefaults(config.Build{
+		ID:      "foo",
+		Targets: []string{"linux_arm64", "darwin_arm64"},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, build.Targets, []string{"linux_arm64", "darwin_arm64"})
+}
+
+func TestWithDefaultsInvalidTarget(t *testing.T) {
+	_, err := Default.WithDefaults(config.Build{
+		ID:      "foo",
+		Targets: []string{"plan9_amd64"},
+	})
+	require.EqualError(t, err, "invalid rust target: plan9_amd64")
+}
+
+func TestBuildInvalidTarget(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	writeCargoProject(t, folder, "foo")
+	ctx := context.New(config.Project{})
+	ctx.Git.CurrentTag = "1.2.3"
+	err := Default.Build(ctx, config.Build{ID: "foo"}, api.Options{
+		Target: "plan9_amd64",
+	})
+	require.EqualError(t, err, "plan9_amd64 is not a valid build target")
+	require.Empty(t, ctx.Artifacts.List())
+}
+
+func TestBinaryNameFromCargoToml(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	writeCargoProject(t, folder, "my-crate")
+	name, err := binaryNameFromCargoToml(folder)
+	require.NoError(t, err)
+	require.Equal(t, "my-crate", name)
+}
+
+func TestBuildModTimestamp(t *testing.T) {
+	// round to seconds since this will be a unix timestamp
+	modTime := time.Now().AddDate(-1, 0, 0).Round(1 * time.Second).UTC()
+
+	folder := testlib.Mktmp(t)
+	writeCargoProject(t, folder, "foo")
+
+	target := goosGoarch()
+	triple := rustTargets[target]
+	builtDir := filepath.Join(folder, "target", triple, "release")
+	require.NoError(t, os.MkdirAll(builtDir, 0o755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(builtDir, "foo"), []byte("bin"), 0o755))
+
+	ctx := context.New(config.Project{
+		Builds: []config.Build{{ID: "foo", Binary: "foo", ModTimestamp: fmt.Sprintf("%d", modTime.Unix())}},
+	})
+	ctx.Git.CurrentTag = "1.2.3"
+	build := ctx.Config.Builds[0]
+	err := Default.Build(ctx, build, api.Options{
+		Target: target,
+		Name:   "foo",
+		Path:   filepath.Join(folder, "dist", target, "foo"),
+	})
+	require.NoError(t, err)
+	require.Len(t, ctx.Artifacts.List(), 1)
+	require.Equal(t, artifact.Binary, ctx.Artifacts.List()[0].Type)
+
+	fi, err := os.Stat(ctx.Artifacts.List()[0].Path)
+	require.NoError(t, err)
+	require.True(t, modTime.Equal(fi.ModTime()), "inconsistent mod times found when specifying ModTimestamp")
+}
+
+func TestBuildFailed(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	writeBrokenCargoProject(t, folder, "foo")
+
+	target := goosGoarch()
+	ctx := context.New(config.Project{
+		Builds: []config.Build{{ID: "foo", Binary: "foo"}},
+	})
+	ctx.Git.CurrentTag = "1.2.3"
+	build := ctx.Config.Builds[0]
+	err := Default.Build(ctx, build, api.Options{
+		Target: target,
+		Name:   "foo",
+		Path:   filepath.Join(folder, "dist", target, "foo"),
+	})
+	require.Error(t, err)
+	require.Empty(t, ctx.Artifacts.List())
+}
+
+func writeCargoProject(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "Cargo.toml"),
+		[]byte("[package]\nname = \""+name+"\"\nversion = \"0.1.0\"\n"),
+		0o644,
+	))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o755))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "src", "main.rs"),
+		[]byte("fn main() {}\n"),
+		0o644,
+	))
+}
+
+// writeBrokenCargoProject writes a cargo project whose source fails to
+// compile, so that `cargo build` returns a non-zero exit code.
+func writeBrokenCargoProject(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "Cargo.toml"),
+		[]byte("[package]\nname = \""+name+"\"\nversion = \"0.1.0\"\n"),
+		0o644,
+	))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0o755))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "src", "main.rs"),
+		[]byte("fn main() { this is not valid rust"),
+		0o644,
+	))
+}
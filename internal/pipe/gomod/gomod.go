@@ -0,0 +1,143 @@
+// Package gomod provides a Pipe that downloads, verifies and
+// optionally vendors Go modules before the first build runs.
+package gomod
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Pipe for go mod.
+type Pipe struct{}
+
+// String returns the description of the pipe.
+func (Pipe) String() string {
+	return "go mod"
+}
+
+// Run the pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	dirs := buildDirs(ctx)
+	for _, dir := range dirs {
+		if err := runGoMod(ctx, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDirs returns the deduplicated set of directories used by the
+// project's builds, defaulting to "." if none is set.
+func buildDirs(ctx *context.Context) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, build := range ctx.Config.Builds {
+		dir := build.Dir
+		if dir == "" {
+			dir = "."
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, ".")
+	}
+	return dirs
+}
+
+// vendorRequested reports whether any build rooted at dir asked for
+// vendoring.
+func vendorRequested(ctx *context.Context, dir string) bool {
+	for _, build := range ctx.Config.Builds {
+		buildDir := build.Dir
+		if buildDir == "" {
+			buildDir = "."
+		}
+		if buildDir == dir && build.Vendor {
+			return true
+		}
+	}
+	return false
+}
+
+func runGoMod(ctx *context.Context, dir string) error {
+	if err := run(dir, "mod", "download"); err != nil {
+		return fmt.Errorf("go mod download failed: %w", err)
+	}
+	if err := run(dir, "mod", "verify"); err != nil {
+		return fmt.Errorf("go mod verify failed: %w", err)
+	}
+
+	mods, err := listModules(dir)
+	if err != nil {
+		return err
+	}
+	for _, m := range mods {
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: fmt.Sprintf("%s@%s", m.Path, m.Version),
+			Path: filepath.Join(dir, "go.sum"),
+			Type: artifact.GoMod,
+			Extra: map[string]interface{}{
+				"Path":    m.Path,
+				"Version": m.Version,
+				"Sum":     m.Sum,
+			},
+		})
+	}
+
+	if vendorRequested(ctx, dir) {
+		if err := run(dir, "mod", "vendor"); err != nil {
+			return fmt.Errorf("go mod vendor failed: %w", err)
+		}
+	}
+	return nil
+}
+
+type module struct {
+	Path    string `json:"Path"`
+	Version string `json:"Version"`
+	Sum     string `json:"Sum"`
+}
+
+// listModules resolves the module list (path, version, h1 hash) for
+// dir by asking `go mod download -json`, which reports the h1 hash
+// recorded in go.sum for each module.
+func listModules(dir string) ([]module, error) {
+	/* #nosec */
+	cmd := exec.Command("go", "mod", "download", "-json")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not list modules: %w", err)
+	}
+
+	var mods []module
+	dec := json.NewDecoder(strings.NewReader(string(out)))
+	for dec.More() {
+		var m module
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("could not parse module list: %w", err)
+		}
+		mods = append(mods, m)
+	}
+	return mods, nil
+}
+
+func run(dir string, args ...string) error {
+	/* #nosec */
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w", string(out), err)
+	}
+	return nil
+}
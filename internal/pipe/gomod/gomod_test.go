@@ -0,0 +1,118 @@
+package gomod
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestBuildDirs(t *testing.T) {
+	ctx := context.New(config.Project{
+		Builds: []config.Build{
+			{Dir: "foo"},
+			{Dir: "foo"},
+			{Dir: "bar"},
+			{},
+		},
+	})
+	require.ElementsMatch(t, buildDirs(ctx), []string{"foo", "bar", "."})
+}
+
+func TestBuildDirsDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.Equal(t, []string{"."}, buildDirs(ctx))
+}
+
+func TestVendorRequested(t *testing.T) {
+	ctx := context.New(config.Project{
+		Builds: []config.Build{
+			{Dir: "foo", Vendor: true},
+			{Dir: "bar"},
+		},
+	})
+	require.True(t, vendorRequested(ctx, "foo"))
+	require.False(t, vendorRequested(ctx, "bar"))
+}
+
+func TestRunVerifiesKnownGoodModule(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	folder := testlib.Mktmp(t)
+	writeModule(t, folder)
+
+	ctx := context.New(config.Project{Builds: []config.Build{{Dir: "."}}})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	var found bool
+	for _, a := range ctx.Artifacts.List() {
+		if a.Type == artifact.GoMod {
+			found = true
+		}
+	}
+	require.True(t, found, "expected at least one GoMod artifact")
+}
+
+func TestRunFailsOnTamperedGoSum(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	folder := testlib.Mktmp(t)
+	writeModule(t, folder)
+
+	tamperGoSum(t, filepath.Join(folder, "go.sum"))
+
+	ctx := context.New(config.Project{Builds: []config.Build{{Dir: "."}}})
+	err := Pipe{}.Run(ctx)
+	require.Error(t, err)
+}
+
+// writeModule writes a minimal module that depends on rsc.io/quote, a
+// small, stable public module, so that `go mod download`/`verify` have
+// an actual go.sum entry to check (and, in TestRunFailsOnTamperedGoSum,
+// to tamper with).
+func writeModule(t *testing.T, dir string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "go.mod"),
+		[]byte("module example.com/foo\n\ngo 1.16\n\nrequire rsc.io/quote v1.5.2\n"),
+		0o644,
+	))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "go.sum"),
+		[]byte(
+			"rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=\n"+
+				"rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=\n"+
+				"rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=\n"+
+				"rsc.io/sampler v1.3.0/go.mod h1:T1hPZKmBbMNahiBKFy5HrXp6adAjACjK9JXDnKaTXpA=\n"+
+				"golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c h1:qgOY6WgZOaTkIIMiVjBQcw93ERBE4m30iBm00nkL0i8=\n"+
+				"golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c/go.mod h1:NqM8EUOU14njkJ3fqMW+pc6Ldnwhi/IjpwHt7yyuwOQ=\n",
+		),
+		0o644,
+	))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(dir, "main.go"),
+		[]byte("package main\n\nimport (\n\t\"fmt\"\n\n\t\"rsc.io/quote\"\n)\n\nfunc main() {\n\tfmt.Println(quote.Hello())\n}\n"),
+		0o644,
+	))
+}
+
+func tamperGoSum(t *testing.T, path string) {
+	t.Helper()
+	bts, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	tampered := strings.Replace(string(bts), "h1:", "h1:TAMPERED", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(tampered), 0o644))
+}
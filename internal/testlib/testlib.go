@@ -0,0 +1,23 @@
+// Package testlib provides small helpers shared across test files.
+package testlib
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Mktmp creates a temp dir, cds into it and returns it, registering a
+// cleanup to restore the previous working dir once the test finishes.
+func Mktmp(t *testing.T) string {
+	t.Helper()
+	previous, err := os.Getwd()
+	require.NoError(t, err)
+	folder := t.TempDir()
+	require.NoError(t, os.Chdir(folder))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(previous))
+	})
+	return folder
+}
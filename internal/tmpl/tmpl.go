@@ -0,0 +1,86 @@
+// Package tmpl provides templating utilities for goreleaser, exposing
+// a small set of fields and funcs (tag, version, date, artifact
+// metadata, env vars, etc) to be used across the config file.
+package tmpl
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Template holds data that can be applied to a template string.
+type Template struct {
+	fields fields
+}
+
+type fields map[string]interface{}
+
+const (
+	tagEnv          = "Tag"
+	versionEnv      = "Version"
+	osEnv           = "Os"
+	archEnv         = "Arch"
+	armEnv          = "Arm"
+	binaryEnv       = "Binary"
+	artifactNameEnv = "ArtifactName"
+	commitEnv       = "Commit"
+	commitDateEnv   = "CommitDate"
+	dateEnv         = "Date"
+	envEnv          = "Env"
+)
+
+// New Template.
+func New(ctx *context.Context) *Template {
+	return &Template{
+		fields: fields{
+			tagEnv:        ctx.Git.CurrentTag,
+			versionEnv:    ctx.Version,
+			commitEnv:     ctx.Git.Commit,
+			commitDateEnv: ctx.Git.CommitDate.UTC(),
+			dateEnv:       ctx.Date.UTC(),
+			envEnv:        ctx.Env,
+		},
+	}
+}
+
+// WithArtifact populates Os, Arch, Arm, Binary and ArtifactName fields
+// from the given artifact, mapping the Os and Arch values through
+// replacements where it has an entry for them.
+func (t *Template) WithArtifact(a *artifact.Artifact, replacements map[string]string) *Template {
+	t.fields[osEnv] = replace(replacements, a.Goos)
+	t.fields[archEnv] = replace(replacements, a.Goarch)
+	t.fields[armEnv] = a.Goarm
+	t.fields[binaryEnv] = a.ExtraOr(binaryEnv, "")
+	t.fields[artifactNameEnv] = a.Name
+	return t
+}
+
+func replace(replacements map[string]string, original string) string {
+	result := replacements[original]
+	if result == "" {
+		return original
+	}
+	return result
+}
+
+// Apply applies the given string against the Template fields and
+// funcs.
+func (t *Template) Apply(s string) (string, error) {
+	var out bytes.Buffer
+	tmpl, err := template.New("tmpl").Option("missingkey=error").Funcs(template.FuncMap{
+		"time": func(s string) string {
+			return time.Now().UTC().Format(s)
+		},
+	}).Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if err := tmpl.Execute(&out, t.fields); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
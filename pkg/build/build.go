@@ -0,0 +1,42 @@
+// Package build defines the builder plugin interface, which can be
+// implemented to add new languages/builders to goreleaser.
+package build
+
+import (
+	"fmt"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Options to be passed down to a builder.
+type Options struct {
+	Name   string
+	Path   string
+	Target string
+	Ext    string
+}
+
+// Builder defines a builder (duh) and will be used to build for all the
+// different targets and platforms, for example: golang, gccgo, etc.
+type Builder interface {
+	WithDefaults(build config.Build) (config.Build, error)
+	Build(ctx *context.Context, build config.Build, options Options) error
+}
+
+var builders = map[string]Builder{}
+
+// Register a new builder for the given language/ID.
+func Register(lang string, builder Builder) {
+	builders[lang] = builder
+}
+
+// For returns the registered builder for the given language/ID, or an
+// error if none is registered.
+func For(lang string) (Builder, error) {
+	builder, ok := builders[lang]
+	if !ok {
+		return nil, fmt.Errorf("no builder registered for %s", lang)
+	}
+	return builder, nil
+}
@@ -0,0 +1,59 @@
+// Package config contains the model and loader of the goreleaser
+// configuration file.
+package config
+
+// HookConfig provides pre and post hooks to run before and after the
+// build.
+type HookConfig struct {
+	Pre  []string `yaml:"pre,omitempty"`
+	Post []string `yaml:"post,omitempty"`
+}
+
+// Build contains the build configuration section.
+type Build struct {
+	ID           string         `yaml:"id,omitempty"`
+	Goos         []string       `yaml:"goos,omitempty"`
+	Goarch       []string       `yaml:"goarch,omitempty"`
+	Goarm        []string       `yaml:"goarm,omitempty"`
+	Gomips       []string       `yaml:"gomips,omitempty"`
+	Targets      []string       `yaml:"targets,omitempty"`
+	Ignore       []IgnoredBuild `yaml:"ignore,omitempty"`
+	Dir          string         `yaml:"dir,omitempty"`
+	Main         string         `yaml:"main,omitempty"`
+	Ldflags      []string       `yaml:"ldflags,omitempty"`
+	Flags        []string       `yaml:"flags,omitempty"`
+	Binary       string         `yaml:"binary,omitempty"`
+	Builder      string         `yaml:"builder,omitempty"`
+	Hooks        HookConfig     `yaml:"hooks,omitempty"`
+	Env          []string       `yaml:"env,omitempty"`
+	Asmflags     []string       `yaml:"asmflags,omitempty"`
+	Gcflags      []string       `yaml:"gcflags,omitempty"`
+	ModTimestamp string         `yaml:"mod_timestamp,omitempty"`
+	GoBinary     string         `yaml:"gobinary,omitempty"`
+	Buildmode    string         `yaml:"buildmode,omitempty"`
+
+	// Compiler selects the Go compiler driver to use: "gc" (the
+	// default, standard `go` toolchain) or "gccgo".
+	Compiler string `yaml:"compiler,omitempty"`
+
+	// Vendor makes the gomod pipe run `go mod vendor` before this
+	// build, and builds it with `-mod=vendor`.
+	Vendor bool `yaml:"vendor,omitempty"`
+}
+
+// IgnoredBuild represents a build ignored by the user.
+type IgnoredBuild struct {
+	Goos   string `yaml:"goos,omitempty"`
+	Goarch string `yaml:"goarch,omitempty"`
+	Goarm  string `yaml:"goarm,omitempty"`
+	Gomips string `yaml:"gomips,omitempty"`
+}
+
+// Project includes all project configuration.
+type Project struct {
+	Builds []Build `yaml:"builds,omitempty"`
+
+	// Cache enables the golang builder's content-hash rebuild cache,
+	// skipping `go build` for targets whose inputs haven't changed.
+	Cache bool `yaml:"cache,omitempty"`
+}
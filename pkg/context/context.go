@@ -0,0 +1,52 @@
+// Package context provides the context used throughout the release
+// pipeline, carrying the parsed configuration as well as the state
+// accumulated as pipes run (git info, artifacts, etc).
+package context
+
+import (
+	"context"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+)
+
+// GitInfo includes tags and commit info.
+type GitInfo struct {
+	CurrentTag string
+	Commit     string
+	CommitDate time.Time
+}
+
+// Context carries along some data through the pipes.
+type Context struct {
+	context.Context
+	Config    config.Project
+	Env       map[string]string
+	Git       GitInfo
+	Artifacts artifact.Artifacts
+	Version   string
+	Date      time.Time
+	Snapshot  bool
+
+	// NoCache disables the golang builder's rebuild cache even when
+	// config.Project.Cache is set. No command-line flag in this module
+	// sets it; callers set the field directly.
+	NoCache bool
+}
+
+// New context.
+func New(config config.Project) *Context {
+	return Wrap(context.Background(), config)
+}
+
+// Wrap wraps an existing context.
+func Wrap(ctx context.Context, config config.Project) *Context {
+	return &Context{
+		Context:   ctx,
+		Config:    config,
+		Env:       map[string]string{},
+		Artifacts: artifact.New(),
+		Date:      time.Now(),
+	}
+}